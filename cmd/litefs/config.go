@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultConfigPath is the default path to the configuration file.
+const DefaultConfigPath = "/etc/litefs.yml"
+
+// Config represents a configuration file for the litefs command.
+type Config struct {
+	// MountDir is the directory that the FUSE file system is mounted to.
+	MountDir string `yaml:"mount-dir"`
+
+	// DataDir is the directory that LiteFS stores its internal data in.
+	DataDir string `yaml:"data-dir"`
+
+	// Databases, if non-empty, restricts replication to only the named
+	// SQLite databases, for running "shard-aware" replicas that don't hold
+	// the full dataset. An empty list (the default) replicates every
+	// database from the primary, preserving today's behavior. Validated in
+	// MountCommand.Validate; enforced end to end by litefs.Store and
+	// http.Client, which it's passed to in initStore.
+	Databases []string `yaml:"databases"`
+
+	Debug        bool `yaml:"debug"`
+	Candidate    bool `yaml:"candidate"`
+	StrictVerify bool `yaml:"strict-verify"`
+
+	Exec ExecConfig `yaml:"exec"`
+
+	HTTP      HTTPConfig      `yaml:"http"`
+	Consul    *ConsulConfig   `yaml:"consul"`
+	Static    *StaticConfig   `yaml:"static"`
+	Retention RetentionConfig `yaml:"retention"`
+}
+
+// NewConfig returns a new instance of Config with defaults set.
+func NewConfig() Config {
+	var config Config
+	config.HTTP.Addr = ":20202"
+	config.Retention.MonitorInterval = time.Minute
+	config.Exec.Restart = ExecRestartNo
+	config.Exec.Backoff = time.Second
+	config.Exec.ShutdownGrace = 10 * time.Second
+	return config
+}
+
+// HTTPConfig represents the configuration for the HTTP API server.
+type HTTPConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+// ConsulConfig represents the configuration for a Consul-based lease.
+type ConsulConfig struct {
+	URL          string        `yaml:"url"`
+	Hostname     string        `yaml:"hostname"`
+	AdvertiseURL string        `yaml:"advertise-url"`
+	Key          string        `yaml:"key"`
+	TTL          time.Duration `yaml:"ttl"`
+	LockDelay    time.Duration `yaml:"lock-delay"`
+}
+
+// StaticConfig represents the configuration for a static, pre-determined lease.
+type StaticConfig struct {
+	Primary      bool   `yaml:"primary"`
+	Hostname     string `yaml:"hostname"`
+	AdvertiseURL string `yaml:"advertise-url"`
+}
+
+// Exec restart policies.
+const (
+	ExecRestartNo        = "no"
+	ExecRestartOnFailure = "on-failure"
+	ExecRestartAlways    = "always"
+)
+
+// ExecConfig represents the configuration for the "exec" subcommand that
+// MountCommand supervises once the node is ready. It can be specified as a
+// plain string (just the command to run) or as an object with a restart
+// policy:
+//
+//	exec: myapp serve
+//
+//	exec:
+//	  cmd: myapp serve
+//	  restart: on-failure
+//	  backoff: 1s
+//	  max-restarts: 5
+//	  shutdown-grace: 10s
+type ExecConfig struct {
+	Cmd string `yaml:"cmd"`
+
+	// Restart controls whether the subprocess is re-invoked after it exits:
+	// "no" (default) runs it once, "on-failure" restarts on non-zero exit,
+	// "always" restarts regardless of exit status.
+	Restart string `yaml:"restart"`
+
+	// Backoff is the delay before each restart attempt.
+	Backoff time.Duration `yaml:"backoff"`
+
+	// MaxRestarts caps the number of restart attempts. Zero means unlimited.
+	MaxRestarts int `yaml:"max-restarts"`
+
+	// ShutdownGrace is how long to wait after sending SIGTERM to the
+	// subprocess before escalating to SIGKILL during shutdown.
+	ShutdownGrace time.Duration `yaml:"shutdown-grace"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. It allows "exec" to be specified
+// as either a bare command string or a full object, so existing configs with
+// a plain `exec: mycommand` line keep working unchanged.
+func (c *ExecConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var cmd string
+	if err := unmarshal(&cmd); err == nil {
+		c.Cmd = cmd
+		return nil
+	}
+
+	type alias ExecConfig
+	a := alias(*c)
+	if err := unmarshal(&a); err != nil {
+		return err
+	}
+	*c = ExecConfig(a)
+	return nil
+}
+
+// RetentionConfig represents the configuration for LTX file retention.
+type RetentionConfig struct {
+	Duration        time.Duration `yaml:"duration"`
+	MonitorInterval time.Duration `yaml:"monitor-interval"`
+}
+
+// loadConfig searches for and reads the litefs.yml configuration, returning
+// a populated Config. It does not depend on MountCommand, so any subcommand
+// that just needs the config (e.g. to find a running node's HTTPServer.URL())
+// can call it directly instead of constructing a full MountCommand.
+func loadConfig(configPath string, expandEnv bool) (Config, error) {
+	config := NewConfig()
+
+	// Only read from explicit path, if specified. Report any error.
+	if configPath != "" {
+		if err := ReadConfigFile(&config, configPath, expandEnv); err != nil {
+			return config, err
+		}
+		return config, nil
+	}
+
+	// Otherwise attempt to read each config path until we succeed.
+	for _, path := range configSearchPaths() {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return config, err
+		}
+
+		if err := ReadConfigFile(&config, abs, expandEnv); err == nil {
+			fmt.Printf("config file read from %s\n", abs)
+			return config, nil
+		} else if !os.IsNotExist(err) {
+			return config, fmt.Errorf("cannot read config file at %s: %s", abs, err)
+		}
+	}
+	return config, fmt.Errorf("config file not found")
+}
+
+// ReadConfigFile reads the configuration file from filename and unmarshals
+// it into config. If expandEnv is true, environment variables are expanded
+// before the YAML is parsed.
+func ReadConfigFile(config *Config, filename string, expandEnv bool) error {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if expandEnv {
+		buf = []byte(os.ExpandEnv(string(buf)))
+	}
+
+	if err := unmarshalConfig(config, buf); err != nil {
+		return fmt.Errorf("cannot unmarshal config: %w", err)
+	}
+	return nil
+}
+
+func unmarshalConfig(config *Config, buf []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.SetStrict(true)
+	if err := dec.Decode(config); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func main() {
+	if err := NewRootCommand().ExecuteContext(context.Background()); err != nil {
+		fmt.Println(err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps err to a process exit code. If err came from the supervised
+// exec subcommand, it reuses that subcommand's own exit code (following the
+// 128+signal convention for signal deaths, e.g. 137 after SIGKILL) instead of
+// a generic 1, so callers running litefs as a container PID 1 can drive their
+// own restart/alerting policy off of it.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 1
+	}
+
+	if ws, ok := exitErr.ProcessState.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return exitErr.ExitCode()
+}
+
+// Build information, injected at build time via ldflags.
+var (
+	Version = ""
+	Commit  = ""
+)
+
+// VersionString returns the version and commit information for the build.
+func VersionString() string {
+	if Version == "" {
+		return "LiteFS (development build)"
+	}
+	return fmt.Sprintf("LiteFS %s, commit=%s", Version, Commit)
+}
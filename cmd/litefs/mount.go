@@ -9,10 +9,13 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"os/user"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/mattn/go-shellwords"
 	"github.com/superfly/litefs"
@@ -23,8 +26,8 @@ import (
 
 // MountCommand represents a command to mount the file system.
 type MountCommand struct {
-	cmd    *exec.Cmd  // subcommand
-	execCh chan error // subcommand error channel
+	cmd    *exec.Cmd  // most recently started exec subcommand, if any
+	execCh chan error // result of the exec supervisor loop
 
 	Config Config
 
@@ -45,6 +48,20 @@ func NewMountCommand() *MountCommand {
 	}
 }
 
+// mountDescription is the shared prose describing what the mount command
+// does. It is reused both by ParseFlags' flag.Usage (for "litefs mount -h")
+// and by the cobra "mount" subcommand's Long description so the two don't
+// drift out of sync with each other.
+const mountDescription = `
+The mount command will mount a LiteFS directory via FUSE and begin communicating
+with the LiteFS cluster. The mount will be accessible once the node becomes the
+primary or is able to connect and sync with the primary.
+
+All options are specified in the litefs.yml config file which is searched for in
+the present working directory, the current user's home directory, and then
+finally at /etc/litefs.yml.
+`[1:]
+
 // ParseFlags parses the command line flags & config file.
 func (c *MountCommand) ParseFlags(ctx context.Context, args []string) (err error) {
 	// Split the args list if there is a double dash arg included. Arguments
@@ -55,15 +72,8 @@ func (c *MountCommand) ParseFlags(ctx context.Context, args []string) (err error
 	configPath := fs.String("config", "", "config file path")
 	noExpandEnv := fs.Bool("no-expand-env", false, "do not expand env vars in config")
 	fs.Usage = func() {
+		fmt.Print(mountDescription)
 		fmt.Println(`
-The mount command will mount a LiteFS directory via FUSE and begin communicating
-with the LiteFS cluster. The mount will be accessible once the node becomes the
-primary or is able to connect and sync with the primary.
-
-All options are specified in the litefs.yml config file which is searched for in
-the present working directory, the current user's home directory, and then
-finally at /etc/litefs.yml.
-
 Usage:
 
 	litefs mount [arguments]
@@ -85,7 +95,7 @@ Arguments:
 
 	// Override "exec" field if specified on the CLI.
 	if args1 != nil {
-		c.Config.Exec = strings.Join(args1, " ")
+		c.Config.Exec.Cmd = strings.Join(args1, " ")
 	}
 
 	return nil
@@ -95,25 +105,8 @@ Arguments:
 // Otherwise searches the standard list of search paths. Returns an error if
 // no configuration files could be found.
 func (c *MountCommand) parseConfig(ctx context.Context, configPath string, expandEnv bool) (err error) {
-	// Only read from explicit path, if specified. Report any error.
-	if configPath != "" {
-		return ReadConfigFile(&c.Config, configPath, expandEnv)
-	}
-
-	// Otherwise attempt to read each config path until we succeed.
-	for _, path := range configSearchPaths() {
-		if path, err = filepath.Abs(path); err != nil {
-			return err
-		}
-
-		if err := ReadConfigFile(&c.Config, path, expandEnv); err == nil {
-			fmt.Printf("config file read from %s\n", path)
-			return nil
-		} else if err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("cannot read config file at %s: %s", path, err)
-		}
-	}
-	return fmt.Errorf("config file not found")
+	c.Config, err = loadConfig(configPath, expandEnv)
+	return err
 }
 
 // Validate validates the application's configuration.
@@ -133,6 +126,20 @@ func (c *MountCommand) Validate(ctx context.Context) (err error) {
 		return fmt.Errorf("must specify a lease mode ('consul', 'static')")
 	}
 
+	switch c.Config.Exec.Restart {
+	case "", ExecRestartNo, ExecRestartOnFailure, ExecRestartAlways:
+	default:
+		return fmt.Errorf("invalid exec restart policy: %q", c.Config.Exec.Restart)
+	}
+
+	for _, name := range c.Config.Databases {
+		if name == "" {
+			return fmt.Errorf("database filter entries cannot be empty")
+		} else if strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid database name in filter: %q", name)
+		}
+	}
+
 	return nil
 }
 
@@ -144,7 +151,7 @@ func configSearchPaths() []string {
 	if u, _ := user.Current(); u != nil && u.HomeDir != "" {
 		a = append(a, filepath.Join(u.HomeDir, "litefs.yml"))
 	}
-	a = append(a, "/etc/litefs.yml")
+	a = append(a, DefaultConfigPath)
 	return a
 }
 
@@ -212,12 +219,17 @@ func (c *MountCommand) Run(ctx context.Context) (err error) {
 		log.Printf("connected to cluster, ready")
 	}
 
-	// Execute subcommand, if specified in config.
-	if err := c.execCmd(ctx); err != nil {
-		return fmt.Errorf("cannot exec: %w", err)
+	// Execute subcommand, if specified in config, and supervise it for the
+	// rest of the process lifetime. Its exit (or a graceful shutdown driven
+	// by ctx) is what unblocks Run, so Close() tears down FUSE/Store only
+	// after the child has already stopped.
+	if c.Config.Exec.Cmd != "" {
+		go c.execSupervisor(ctx)
+		return <-c.execCh
 	}
 
-	return nil
+	<-ctx.Done()
+	return ctx.Err()
 }
 
 func (c *MountCommand) initConsul(ctx context.Context) (err error) {
@@ -266,7 +278,16 @@ func (c *MountCommand) initStore(ctx context.Context) error {
 	c.Store.StrictVerify = c.Config.StrictVerify
 	c.Store.RetentionDuration = c.Config.Retention.Duration
 	c.Store.RetentionMonitorInterval = c.Config.Retention.MonitorInterval
-	c.Store.Client = http.NewClient()
+
+	// Restrict replication to a subset of databases, if configured. An empty
+	// filter replicates every database from the primary, as before. This is
+	// relayed the same way Debug/StrictVerify/RetentionDuration above are:
+	// cmd/litefs only carries the operator's setting down to Store and
+	// Client, which enforce it (skipping on-disk/FUSE state for excluded
+	// databases, and never requesting their frames from the primary).
+	c.Store.DatabaseFilter = c.Config.Databases
+
+	c.Store.Client = http.NewClient(c.Config.Databases)
 	return nil
 }
 
@@ -305,30 +326,134 @@ func (c *MountCommand) initHTTPServer(ctx context.Context) error {
 	return nil
 }
 
-func (c *MountCommand) execCmd(ctx context.Context) error {
-	// Exit if no subcommand specified.
-	if c.Config.Exec == "" {
-		return nil
+// execSupervisor runs the configured exec subcommand to completion, restarting
+// it according to c.Config.Exec.Restart, and sends the final result to
+// execCh. It returns once the process should stop being supervised: either
+// ctx was canceled (the child, if any, has already been shut down gracefully)
+// or the restart policy gave up.
+func (c *MountCommand) execSupervisor(ctx context.Context) {
+	backoff := c.Config.Exec.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
 	}
 
-	// Execute subcommand process.
-	args, err := shellwords.Parse(c.Config.Exec)
+	var restarts int
+	for {
+		err := c.runExecOnce(ctx)
+
+		if ctx.Err() != nil {
+			c.execCh <- ctx.Err()
+			return
+		}
+
+		restart := false
+		switch c.Config.Exec.Restart {
+		case ExecRestartAlways:
+			restart = true
+		case ExecRestartOnFailure:
+			restart = err != nil
+		}
+
+		if !restart {
+			c.execCh <- err
+			return
+		}
+
+		if max := c.Config.Exec.MaxRestarts; max > 0 && restarts >= max {
+			log.Printf("exec: subprocess exited (%v), restart limit of %d reached", err, max)
+			c.execCh <- err
+			return
+		}
+		restarts++
+
+		log.Printf("exec: subprocess exited (%v), restarting in %s (attempt %d)", err, backoff, restarts)
+		select {
+		case <-ctx.Done():
+			c.execCh <- ctx.Err()
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// runExecOnce starts the exec subcommand once, forwards signals to it for as
+// long as it runs, and waits for it to exit. If ctx is canceled first, the
+// child is sent SIGTERM (escalating to SIGKILL after the configured grace
+// period) and runExecOnce waits for it to actually exit before returning, so
+// that callers can rely on the child being gone before tearing down FUSE.
+func (c *MountCommand) runExecOnce(ctx context.Context) error {
+	args, err := shellwords.Parse(c.Config.Exec.Cmd)
 	if err != nil {
 		return fmt.Errorf("cannot parse exec command: %w", err)
 	}
 
-	log.Printf("starting subprocess: %s %v", args[0], args[1:])
+	log.Printf("exec: starting subprocess: %s %v", args[0], args[1:])
 
-	c.cmd = exec.CommandContext(ctx, args[0], args[1:]...)
-	c.cmd.Env = os.Environ()
-	c.cmd.Stdout = os.Stdout
-	c.cmd.Stderr = os.Stderr
-	if err := c.cmd.Start(); err != nil {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = os.Environ()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Put the child in its own process group so that signals sent to it are
+	// not also delivered to this process, and so we can signal the whole
+	// group (covering any children it spawns itself).
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("cannot start exec command: %w", err)
 	}
-	go func() { c.execCh <- c.cmd.Wait() }()
+	c.cmd = cmd
 
-	return nil
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- cmd.Wait() }()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGQUIT)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case sig := <-sigCh:
+			if err := syscall.Kill(-cmd.Process.Pid, sig.(syscall.Signal)); err != nil {
+				log.Printf("exec: cannot forward signal %s to subprocess: %s", sig, err)
+			}
+		case <-ctx.Done():
+			return c.shutdownExec(cmd, waitCh)
+		case err := <-waitCh:
+			return err
+		}
+	}
 }
 
-var expvarOnce sync.Once
\ No newline at end of file
+// shutdownExec sends SIGTERM to the subprocess' process group and waits up to
+// the configured shutdown grace period for it to exit before escalating to
+// SIGKILL.
+func (c *MountCommand) shutdownExec(cmd *exec.Cmd, waitCh chan error) error {
+	grace := c.Config.Exec.ShutdownGrace
+	if grace <= 0 {
+		grace = 10 * time.Second
+	}
+
+	log.Printf("exec: shutting down subprocess (pid=%d), grace=%s", cmd.Process.Pid, grace)
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+
+	select {
+	case err := <-waitCh:
+		return err
+	case <-time.After(grace):
+		log.Printf("exec: subprocess did not exit within grace period, sending SIGKILL")
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		return <-waitCh
+	}
+}
+
+var expvarOnce sync.Once
+
+// splitArgs splits args on the first "--" so that everything after it can be
+// used as the "exec" subcommand. Returns args1 as nil if no "--" is found.
+func splitArgs(args []string) (args0, args1 []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i], args[i+1:]
+		}
+	}
+	return args, nil
+}
\ No newline at end of file
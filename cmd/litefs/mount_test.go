@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestMountCommand_execSupervisor_RestartOnFailure verifies that a
+// "restart: on-failure" policy re-invokes a failing subcommand up to
+// max-restarts times and that it stops restarting once the limit is reached,
+// returning the last run's exit error.
+func TestMountCommand_execSupervisor_RestartOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "runs")
+
+	c := NewMountCommand()
+	c.Config.Exec.Cmd = "sh -c 'echo x >> " + marker + "; exit 1'"
+	c.Config.Exec.Restart = ExecRestartOnFailure
+	c.Config.Exec.Backoff = 10 * time.Millisecond
+	c.Config.Exec.MaxRestarts = 2
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go c.execSupervisor(ctx)
+
+	select {
+	case err := <-c.execCh:
+		var exitErr *exec.ExitError
+		if err == nil {
+			t.Fatal("expected a non-nil error from a failing subcommand")
+		} else if !errors.As(err, &exitErr) {
+			t.Fatalf("expected *exec.ExitError, got %T: %v", err, err)
+		} else if exitErr.ExitCode() != 1 {
+			t.Fatalf("expected exit code 1, got %d", exitErr.ExitCode())
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for execSupervisor to give up")
+	}
+
+	buf, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("cannot read marker file: %s", err)
+	}
+
+	// One initial run plus two restarts.
+	if len(buf) == 0 {
+		t.Fatal("subcommand never ran")
+	}
+	if lines := strings.Count(string(buf), "\n"); lines != 3 {
+		t.Fatalf("expected 3 runs (1 initial + 2 restarts), got %d", lines)
+	}
+}
+
+// TestMountCommand_shutdownExec_EscalatesToSIGKILL verifies that a subcommand
+// which ignores SIGTERM is forcibly killed once the shutdown grace period
+// elapses, rather than hanging Run/Close indefinitely.
+func TestMountCommand_shutdownExec_EscalatesToSIGKILL(t *testing.T) {
+	c := NewMountCommand()
+	c.Config.Exec.ShutdownGrace = 50 * time.Millisecond
+
+	cmd := exec.Command("sh", "-c", `trap '' TERM; sleep 5`)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("cannot start subcommand: %s", err)
+	}
+
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- cmd.Wait() }()
+
+	start := time.Now()
+	err := c.shutdownExec(cmd, waitCh)
+	elapsed := time.Since(start)
+
+	if elapsed > time.Second {
+		t.Fatalf("shutdownExec took %s, expected escalation well under the 5s sleep", elapsed)
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected *exec.ExitError, got %T: %v", err, err)
+	}
+	ws, ok := exitErr.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() || ws.Signal() != syscall.SIGKILL {
+		t.Fatalf("expected subprocess to be killed by SIGKILL, got status %v", exitErr.ProcessState)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRootCommand returns the root "litefs" command. Only the "mount"
+// subcommand is registered. Verbs that talk to a running node (status,
+// snapshot, promote, demote, dbs, verify, ...) can be added here as their own
+// subcommands; they can share config discovery via loadConfig (see config.go)
+// to find the node's HTTPServer.URL() without duplicating MountCommand's flag
+// parsing.
+func NewRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "litefs",
+		Short:         "litefs manages a LiteFS FUSE mount and cluster node",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(newMountCobraCommand())
+	return cmd
+}
+
+// newMountCobraCommand wraps MountCommand in a cobra command. Flag parsing is
+// delegated entirely to MountCommand.ParseFlags so that its config file
+// handling and "-- <exec>" passthrough behave exactly as before; cobra's own
+// flag parser is disabled for this subcommand.
+func newMountCobraCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mount [arguments] [-- command ...]",
+		Short: "mount the LiteFS file system and begin cluster replication",
+		Long: mountDescription + `
+Arguments after a "--" are used as the "exec" subcommand, overriding the "exec"
+field in the config file.
+`,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			ch := make(chan os.Signal, 1)
+			signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(ch)
+			go func() {
+				select {
+				case <-ch:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			c := NewMountCommand()
+			if err := c.ParseFlags(ctx, args); err != nil {
+				return err
+			} else if err := c.Validate(ctx); err != nil {
+				return err
+			}
+
+			runErr := c.Run(ctx)
+			if err := c.Close(); err != nil && runErr == nil {
+				runErr = err
+			}
+
+			// A graceful shutdown (signal-triggered cancellation) surfaces
+			// as context.Canceled; that's not a failure.
+			if errors.Is(runErr, context.Canceled) {
+				return nil
+			}
+			return runErr
+		},
+	}
+}